@@ -2,21 +2,134 @@ package main
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// ErrListChangedOnDisk indicates the backing file was modified since it was
+// last loaded or saved, so a naive overwrite would discard those changes.
+var ErrListChangedOnDisk = errors.New("todo list changed on disk since it was loaded; reload or force-save to overwrite")
+
+// todoTxtDateFormat is the date layout used throughout the todo.txt format.
+const todoTxtDateFormat = "2006-01-02"
+
 // Task represents a single todo item
 type Task struct {
-	ID          int       `json:"id"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	CreatedAt   time.Time  `json:"created_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Fields parsed from todo.txt-style metadata. Priority comes from the
+	// leading (A) marker, which is not kept in Description; the others come
+	// from tokens that stay in Description.
+	Priority string            `json:"priority,omitempty"`
+	Projects []string          `json:"projects,omitempty"`
+	Contexts []string          `json:"contexts,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	DueDate  *time.Time        `json:"due_date,omitempty"`
+}
+
+// String renders the task in todo.txt line format.
+func (t Task) String() string {
+	var b strings.Builder
+	if t.Completed {
+		b.WriteString("x ")
+		if t.CompletedAt != nil {
+			b.WriteString(t.CompletedAt.Format(todoTxtDateFormat))
+			b.WriteString(" ")
+		}
+	}
+	if t.Priority != "" {
+		b.WriteString(fmt.Sprintf("(%s) ", t.Priority))
+	}
+	if !t.CreatedAt.IsZero() {
+		b.WriteString(t.CreatedAt.Format(todoTxtDateFormat))
+		b.WriteString(" ")
+	}
+	b.WriteString(t.Description)
+	return b.String()
+}
+
+// ParseTask parses a single todo.txt-formatted line into a Task. It
+// recognizes an "x <date>" completion prefix, a "(A)" priority marker, a
+// leading creation date, and inline +project, @context and key:value tokens
+// (notably due:YYYY-MM-DD). The completion prefix, priority marker and
+// creation date are removed from Description; the inline tokens stay in it.
+// String writes the removed parts back, so the line round-trips unchanged.
+func ParseTask(line string) (Task, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Task{}, fmt.Errorf("empty todo.txt line")
+	}
+
+	task := Task{}
+	i := 0
+
+	if fields[i] == "x" {
+		task.Completed = true
+		i++
+		if i < len(fields) {
+			if completedAt, err := time.Parse(todoTxtDateFormat, fields[i]); err == nil {
+				task.CompletedAt = &completedAt
+				i++
+			}
+		}
+	}
+
+	if i < len(fields) && len(fields[i]) == 3 && fields[i][0] == '(' && fields[i][2] == ')' {
+		if p := fields[i][1]; p >= 'A' && p <= 'Z' {
+			task.Priority = string(p)
+			i++
+		}
+	}
+
+	if i < len(fields) {
+		if createdAt, err := time.Parse(todoTxtDateFormat, fields[i]); err == nil {
+			task.CreatedAt = createdAt
+			i++
+		}
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+
+	descFields := fields[i:]
+	for _, f := range descFields {
+		switch {
+		case strings.HasPrefix(f, "+") && len(f) > 1:
+			task.Projects = append(task.Projects, strings.TrimPrefix(f, "+"))
+		case strings.HasPrefix(f, "@") && len(f) > 1:
+			task.Contexts = append(task.Contexts, strings.TrimPrefix(f, "@"))
+		case strings.Contains(f, ":"):
+			key, value, ok := strings.Cut(f, ":")
+			if !ok || key == "" || value == "" {
+				continue
+			}
+			if task.Tags == nil {
+				task.Tags = make(map[string]string)
+			}
+			task.Tags[key] = value
+			if key == "due" {
+				if due, err := time.Parse(todoTxtDateFormat, value); err == nil {
+					task.DueDate = &due
+				}
+			}
+		}
+	}
+	task.Description = strings.Join(descFields, " ")
+
+	return task, nil
 }
 
 // TodoList manages a collection of tasks
@@ -24,6 +137,10 @@ type TodoList struct {
 	Tasks    []Task `json:"tasks"`
 	NextID   int    `json:"next_id"`
 	filename string
+
+	// loadedHash is the content hash of filename as of the last successful
+	// load or save, used by diskChanged to detect concurrent edits.
+	loadedHash string
 }
 
 // NewTodoList creates a new TodoList instance
@@ -47,48 +164,256 @@ func (tl *TodoList) LoadFromFile() error {
 	defer file.Close()
 
 	decoder := json.NewDecoder(file)
-	return decoder.Decode(tl)
+	if err := decoder.Decode(tl); err != nil {
+		return err
+	}
+
+	hash, err := hashFile(tl.filename)
+	if err != nil {
+		return err
+	}
+	tl.loadedHash = hash
+	return nil
 }
 
-// SaveToFile saves tasks to a JSON file
-func (tl *TodoList) SaveToFile() error {
-	file, err := os.Create(tl.filename)
+// diskChanged reports whether the backing file's contents differ from what
+// was last loaded or saved, e.g. because another instance modified it.
+func (tl *TodoList) diskChanged() (bool, error) {
+	current, err := hashFile(tl.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tl.loadedHash != "", nil
+		}
+		return false, err
+	}
+	return current != tl.loadedHash, nil
+}
+
+// SaveToFile saves tasks to a JSON file. It writes to a temporary file and
+// renames it into place so a crash mid-write cannot truncate the store. If
+// the file changed on disk since it was last loaded, it returns
+// ErrListChangedOnDisk unless force is true.
+func (tl *TodoList) SaveToFile(force bool) error {
+	if !force {
+		changed, err := tl.diskChanged()
+		if err != nil {
+			return err
+		}
+		if changed {
+			return ErrListChangedOnDisk
+		}
+	}
+
+	tmpPath := tl.filename + ".tmp"
+	file, err := os.Create(tmpPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(tl)
+	if err := encoder.Encode(tl); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, tl.filename); err != nil {
+		return err
+	}
+
+	hash, err := hashFile(tl.filename)
+	if err != nil {
+		return err
+	}
+	tl.loadedHash = hash
+	return nil
+}
+
+// LoadFromTodoTxt imports tasks from a todo.txt-formatted file, appending
+// each parsed line to the list under a freshly assigned ID.
+func (tl *TodoList) LoadFromTodoTxt(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		task, err := ParseTask(line)
+		if err != nil {
+			return fmt.Errorf("parsing line %q: %w", line, err)
+		}
+		task.ID = tl.NextID
+		tl.Tasks = append(tl.Tasks, task)
+		tl.NextID++
+	}
+	return scanner.Err()
 }
 
-// AddTask adds a new task to the list
+// SaveToTodoTxt exports the current tasks to path in todo.txt format, one
+// task per line.
+func (tl *TodoList) SaveToTodoTxt(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, task := range tl.Tasks {
+		if _, err := fmt.Fprintln(writer, task.String()); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// AddTask adds a new task to the list, extracting any leading (A) priority
+// marker plus +project, @context and key:value (e.g. due:, rec:) tokens
+// from description via ParseTask. Description is set to parsed.Description
+// so those recognized tokens aren't duplicated when the task round-trips
+// through String/SaveToTodoTxt.
 func (tl *TodoList) AddTask(description string) {
+	parsed, _ := ParseTask(description)
 	task := Task{
 		ID:          tl.NextID,
-		Description: description,
+		Description: parsed.Description,
 		Completed:   false,
 		CreatedAt:   time.Now(),
+		Priority:    parsed.Priority,
+		Projects:    parsed.Projects,
+		Contexts:    parsed.Contexts,
+		Tags:        parsed.Tags,
+		DueDate:     parsed.DueDate,
 	}
 	tl.Tasks = append(tl.Tasks, task)
 	tl.NextID++
 }
 
-// CompleteTask marks a task as completed
-func (tl *TodoList) CompleteTask(id int) error {
+// parseRecurrence parses a todo.txt rec: value like "1d", "2w", "+1m". A
+// leading "+" anchors the next occurrence to the task's original due date
+// instead of the completion time.
+func parseRecurrence(spec string) (amount int, unit byte, anchored bool, err error) {
+	if spec == "" {
+		return 0, 0, false, fmt.Errorf("empty recurrence")
+	}
+	if spec[0] == '+' {
+		anchored = true
+		spec = spec[1:]
+	}
+	if len(spec) < 2 {
+		return 0, 0, false, fmt.Errorf("invalid recurrence %q", spec)
+	}
+
+	unit = spec[len(spec)-1]
+	amount, err = strconv.Atoi(spec[:len(spec)-1])
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("invalid recurrence %q: %w", spec, err)
+	}
+
+	switch unit {
+	case 'd', 'w', 'm', 'y':
+	default:
+		return 0, 0, false, fmt.Errorf("unknown recurrence unit %q", string(unit))
+	}
+
+	return amount, unit, anchored, nil
+}
+
+// addRecurrenceInterval returns from advanced by amount units of unit
+// ('d'ays, 'w'eeks, 'm'onths, 'y'ears).
+func addRecurrenceInterval(from time.Time, amount int, unit byte) time.Time {
+	switch unit {
+	case 'd':
+		return from.AddDate(0, 0, amount)
+	case 'w':
+		return from.AddDate(0, 0, amount*7)
+	case 'm':
+		return from.AddDate(0, amount, 0)
+	case 'y':
+		return from.AddDate(amount, 0, 0)
+	default:
+		return from
+	}
+}
+
+// CompleteTask marks a task as completed. If the task carries a rec: tag,
+// it also appends the next recurring instance and returns its ID; newID is
+// 0 when the completed task does not recur.
+func (tl *TodoList) CompleteTask(id int) (newID int, err error) {
 	for i := range tl.Tasks {
-		if tl.Tasks[i].ID == id {
-			if tl.Tasks[i].Completed {
-				return fmt.Errorf("task %d is already completed", id)
+		if tl.Tasks[i].ID != id {
+			continue
+		}
+		if tl.Tasks[i].Completed {
+			return 0, fmt.Errorf("task %d is already completed", id)
+		}
+
+		recSpec, recurs := tl.Tasks[i].Tags["rec"]
+		var amount int
+		var unit byte
+		var anchored bool
+		if recurs {
+			amount, unit, anchored, err = parseRecurrence(recSpec)
+			if err != nil {
+				return 0, fmt.Errorf("task %d has an invalid recurrence: %w", id, err)
 			}
-			tl.Tasks[i].Completed = true
-			now := time.Now()
-			tl.Tasks[i].CompletedAt = &now
-			return nil
 		}
+
+		now := time.Now()
+		tl.Tasks[i].Completed = true
+		tl.Tasks[i].CompletedAt = &now
+
+		if !recurs {
+			return 0, nil
+		}
+
+		anchor := now
+		if anchored && tl.Tasks[i].DueDate != nil {
+			anchor = *tl.Tasks[i].DueDate
+		}
+		due := addRecurrenceInterval(anchor, amount, unit)
+
+		next := tl.Tasks[i]
+		next.ID = tl.NextID
+		next.CreatedAt = now
+		next.Completed = false
+		next.CompletedAt = nil
+		next.DueDate = &due
+
+		tags := make(map[string]string, len(next.Tags))
+		for k, v := range next.Tags {
+			tags[k] = v
+		}
+		if _, hasDue := tags["due"]; hasDue {
+			tags["due"] = due.Format(todoTxtDateFormat)
+		}
+		next.Tags = tags
+
+		fields := strings.Fields(next.Description)
+		for j, f := range fields {
+			if strings.HasPrefix(f, "due:") {
+				fields[j] = "due:" + due.Format(todoTxtDateFormat)
+			}
+		}
+		next.Description = strings.Join(fields, " ")
+
+		tl.Tasks = append(tl.Tasks, next)
+		newID = tl.NextID
+		tl.NextID++
+		return newID, nil
 	}
-	return fmt.Errorf("task with ID %d not found", id)
+	return 0, fmt.Errorf("task with ID %d not found", id)
 }
 
 // DeleteTask removes a task from the list
@@ -102,27 +427,201 @@ func (tl *TodoList) DeleteTask(id int) error {
 	return fmt.Errorf("task with ID %d not found", id)
 }
 
-// ListTasks displays all tasks
-func (tl *TodoList) ListTasks(showCompleted bool) {
-	if len(tl.Tasks) == 0 {
-		fmt.Println("No tasks found.")
-		return
+// DueWindow narrows ListTasks results to a due-date range.
+type DueWindow string
+
+const (
+	DueWindowNone    DueWindow = ""
+	DueWindowOverdue DueWindow = "overdue"
+	DueWindowToday   DueWindow = "today"
+	DueWindowWeek    DueWindow = "week"
+)
+
+// SortBy selects the ordering ListTasks applies to its results.
+type SortBy string
+
+const (
+	SortByNone       SortBy = ""
+	SortByPriority   SortBy = "priority"
+	SortByDue        SortBy = "due"
+	SortByCreated    SortBy = "created"
+	SortByUsefulness SortBy = "usefulness"
+)
+
+// ListOptions controls the filtering, search and ordering ListTasks applies.
+type ListOptions struct {
+	ShowCompleted bool
+	Project       string
+	Context       string
+	Due           DueWindow
+	Search        string
+	SortBy        SortBy
+}
+
+// parseListArgs turns the free-form argument string following "list" into
+// ListOptions, recognizing +project, @context, due:<window> and
+// --sort=<key> tokens; any other words become a free-text search term.
+func parseListArgs(args string, showCompleted bool) ListOptions {
+	opts := ListOptions{ShowCompleted: showCompleted}
+	var searchTerms []string
+
+	for _, field := range strings.Fields(args) {
+		switch {
+		case strings.HasPrefix(field, "+") && len(field) > 1:
+			opts.Project = strings.TrimPrefix(field, "+")
+		case strings.HasPrefix(field, "@") && len(field) > 1:
+			opts.Context = strings.TrimPrefix(field, "@")
+		case strings.HasPrefix(field, "due:"):
+			opts.Due = DueWindow(strings.TrimPrefix(field, "due:"))
+		case strings.HasPrefix(field, "--sort="):
+			opts.SortBy = SortBy(strings.TrimPrefix(field, "--sort="))
+		default:
+			searchTerms = append(searchTerms, field)
+		}
 	}
 
-	fmt.Println("\n=== TODO LIST ===")
+	opts.Search = strings.Join(searchTerms, " ")
+	return opts
+}
+
+// inDueWindow reports whether a task's due date falls within window.
+func inDueWindow(t Task, window DueWindow) bool {
+	if window == DueWindowNone {
+		return true
+	}
+	if t.DueDate == nil {
+		return false
+	}
+
+	now := time.Now()
+	switch window {
+	case DueWindowOverdue:
+		return t.DueDate.Before(now)
+	case DueWindowToday:
+		// due:YYYY-MM-DD is parsed in UTC, so compare calendar days in UTC
+		// too; otherwise the window misses or over-matches near midnight
+		// for any non-UTC local zone.
+		due := t.DueDate.UTC()
+		today := now.UTC()
+		return due.Year() == today.Year() && due.YearDay() == today.YearDay()
+	case DueWindowWeek:
+		return !t.DueDate.Before(now) && t.DueDate.Before(now.AddDate(0, 0, 7))
+	default:
+		return true
+	}
+}
+
+// containsString reports whether target is present in list.
+func containsString(list []string, target string) bool {
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
+
+// farFuture sorts after any realistic due date, used to push tasks without
+// one to the end of a due-date sort.
+var farFuture = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func dueRank(t Task) time.Time {
+	if t.DueDate == nil {
+		return farFuture
+	}
+	return *t.DueDate
+}
+
+func priorityRank(t Task) int {
+	if t.Priority == "" {
+		return int('Z') + 1
+	}
+	return int(t.Priority[0])
+}
+
+// usefulness scores a task by combining its priority with how soon it is
+// due, mirroring the "usefulness" sort found in other todo.txt tools.
+func usefulness(t Task) float64 {
+	score := 0.0
+	if t.Priority != "" {
+		score += float64('Z'-t.Priority[0]+1) * 10
+	}
+	if t.DueDate != nil {
+		days := time.Until(*t.DueDate).Hours() / 24
+		switch {
+		case days < 0:
+			score += 100
+		case days < 1:
+			score += 50
+		case days < 7:
+			score += 20
+		default:
+			score += 5
+		}
+	}
+	return score
+}
+
+func sortTasks(tasks []Task, by SortBy) {
+	switch by {
+	case SortByPriority:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return priorityRank(tasks[i]) < priorityRank(tasks[j])
+		})
+	case SortByDue:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return dueRank(tasks[i]).Before(dueRank(tasks[j]))
+		})
+	case SortByCreated:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		})
+	case SortByUsefulness:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return usefulness(tasks[i]) > usefulness(tasks[j])
+		})
+	}
+}
+
+// ListTasks displays tasks matching opts, after filtering and sorting them.
+func (tl *TodoList) ListTasks(opts ListOptions) {
+	filtered := make([]Task, 0, len(tl.Tasks))
 	for _, task := range tl.Tasks {
-		if !showCompleted && task.Completed {
+		if !opts.ShowCompleted && task.Completed {
 			continue
 		}
-		
+		if opts.Project != "" && !containsString(task.Projects, opts.Project) {
+			continue
+		}
+		if opts.Context != "" && !containsString(task.Contexts, opts.Context) {
+			continue
+		}
+		if !inDueWindow(task, opts.Due) {
+			continue
+		}
+		if opts.Search != "" && !strings.Contains(strings.ToLower(task.Description), strings.ToLower(opts.Search)) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	sortTasks(filtered, opts.SortBy)
+
+	if len(filtered) == 0 {
+		fmt.Println("No tasks found.")
+		return
+	}
+
+	fmt.Println("\n=== TODO LIST ===")
+	for _, task := range filtered {
 		status := "[ ]"
 		if task.Completed {
 			status = "[âœ“]"
 		}
-		
+
 		fmt.Printf("%s %d: %s\n", status, task.ID, task.Description)
 		fmt.Printf("    Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04"))
-		
+
 		if task.Completed && task.CompletedAt != nil {
 			fmt.Printf("    Completed: %s\n", task.CompletedAt.Format("2006-01-02 15:04"))
 		}
@@ -130,6 +629,208 @@ func (tl *TodoList) ListTasks(showCompleted bool) {
 	}
 }
 
+// hashFile returns a content hash of path, used to detect whether an
+// external editor actually changed anything.
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// stripIDToken removes every id:<n> token, used internally by
+// EditExternal to track a line's identity across an edit, from a todo.txt
+// description.
+func stripIDToken(description string) string {
+	fields := strings.Fields(description)
+	filtered := fields[:0]
+	for _, f := range fields {
+		if strings.HasPrefix(f, "id:") {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	return strings.Join(filtered, " ")
+}
+
+// EditExternal writes the current tasks to a temporary todo.txt file, one
+// line per task tagged with a hidden id:<n> token, opens it in $EDITOR, and
+// on exit re-parses it and applies the result back onto the list: a
+// surviving id:<n> updates that task in place (including completions and
+// reordering), a line with no matching id becomes a new task, and any
+// original task whose id no longer appears is deleted. It does nothing if
+// the file is unchanged, and refuses to apply the edit if the file fails to
+// parse.
+func (tl *TodoList) EditExternal() error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return fmt.Errorf("$EDITOR is not set")
+	}
+
+	tmpFile, err := os.CreateTemp("", "todo-edit-*.txt")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	writer := bufio.NewWriter(tmpFile)
+	for _, task := range tl.Tasks {
+		if _, err := fmt.Fprintf(writer, "%s id:%d\n", task.String(), task.ID); err != nil {
+			tmpFile.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	before, err := hashFile(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(editor, tmpPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", editor, err)
+	}
+
+	after, err := hashFile(tmpPath)
+	if err != nil {
+		return err
+	}
+	if before == after {
+		return nil
+	}
+
+	edited, err := os.Open(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer edited.Close()
+
+	var parsed []Task
+	scanner := bufio.NewScanner(edited)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		task, err := ParseTask(line)
+		if err != nil {
+			return fmt.Errorf("refusing to apply edit, parsing line %q: %w", line, err)
+		}
+		parsed = append(parsed, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	originalByID := make(map[int]bool, len(tl.Tasks))
+	for _, t := range tl.Tasks {
+		originalByID[t.ID] = true
+	}
+
+	updated := make([]Task, 0, len(parsed))
+	nextID := tl.NextID
+	for _, task := range parsed {
+		matchedID, hasID := -1, false
+		if idStr, ok := task.Tags["id"]; ok {
+			if n, err := strconv.Atoi(idStr); err == nil && originalByID[n] {
+				matchedID, hasID = n, true
+			}
+		}
+
+		delete(task.Tags, "id")
+		if len(task.Tags) == 0 {
+			task.Tags = nil
+		}
+		task.Description = stripIDToken(task.Description)
+
+		if hasID {
+			task.ID = matchedID
+		} else {
+			task.ID = nextID
+			nextID++
+		}
+		updated = append(updated, task)
+	}
+
+	tl.Tasks = updated
+	tl.NextID = nextID
+	return nil
+}
+
+// StatusLine renders a single JSON object describing tasks matching filter,
+// suitable for i3blocks/i3status/waybar consumption. filter is parsed the
+// same way as the "list" command's arguments. The state flips to "Warning"
+// when any matching pending task is due within the next 24 hours or is
+// already past its due date.
+func (tl *TodoList) StatusLine(filter string) string {
+	opts := parseListArgs(filter, true)
+
+	var total, done int
+	warning := false
+	soon := time.Now().Add(24 * time.Hour)
+
+	for _, task := range tl.Tasks {
+		if opts.Project != "" && !containsString(task.Projects, opts.Project) {
+			continue
+		}
+		if opts.Context != "" && !containsString(task.Contexts, opts.Context) {
+			continue
+		}
+		if !inDueWindow(task, opts.Due) {
+			continue
+		}
+		if opts.Search != "" && !strings.Contains(strings.ToLower(task.Description), strings.ToLower(opts.Search)) {
+			continue
+		}
+
+		total++
+		if task.Completed {
+			done++
+		} else if task.DueDate != nil && task.DueDate.Before(soon) {
+			warning = true
+		}
+	}
+
+	state := "Idle"
+	if warning {
+		state = "Warning"
+	}
+
+	label := filter
+	if label == "" {
+		label = "all"
+	}
+
+	payload := struct {
+		Icon  string `json:"icon"`
+		State string `json:"state"`
+		Text  string `json:"text"`
+	}{
+		Icon:  "tasks",
+		State: state,
+		Text:  fmt.Sprintf("%s: %d/%d", label, done, total),
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Sprintf(`{"icon":"tasks","state":"Warning","text":"error: %v"}`, err)
+	}
+	return string(data)
+}
+
 // GetStats returns statistics about tasks
 func (tl *TodoList) GetStats() (total, completed, pending int) {
 	total = len(tl.Tasks)
@@ -160,12 +861,18 @@ func main() {
 		
 		fmt.Println("\nCommands:")
 		fmt.Println("1. add <description>    - Add a new task")
-		fmt.Println("2. list                 - List pending tasks")
-		fmt.Println("3. listall              - List all tasks")
-		fmt.Println("4. complete <id>        - Mark task as completed")
+		fmt.Println("2. list [filters]       - List pending tasks, e.g. list +work @home due:today --sort=due")
+		fmt.Println("3. listall [filters]    - List all tasks, including completed ones")
+		fmt.Println("4. complete <id>        - Mark task as completed (rec: tasks spawn their next instance)")
 		fmt.Println("5. delete <id>          - Delete a task")
 		fmt.Println("6. stats                - Show statistics")
-		fmt.Println("7. quit                 - Exit the application")
+		fmt.Println("7. import <file>        - Import tasks from a todo.txt file")
+		fmt.Println("8. export <file>        - Export tasks to a todo.txt file")
+		fmt.Println("9. edit                 - Bulk-edit tasks in $EDITOR")
+		fmt.Println("10. save [--force]      - Save tasks now, optionally overwriting disk changes")
+		fmt.Println("11. reload              - Reload tasks from disk")
+		fmt.Println("12. status [filters]    - Print an i3status/waybar-style JSON status line")
+		fmt.Println("13. quit                - Exit the application")
 		
 		fmt.Print("\nEnter command: ")
 		
@@ -191,10 +898,18 @@ func main() {
 			fmt.Printf("Task added successfully!\n")
 			
 		case "list":
-			todoList.ListTasks(false)
-			
+			args := ""
+			if len(parts) > 1 {
+				args = parts[1]
+			}
+			todoList.ListTasks(parseListArgs(args, false))
+
 		case "listall":
-			todoList.ListTasks(true)
+			args := ""
+			if len(parts) > 1 {
+				args = parts[1]
+			}
+			todoList.ListTasks(parseListArgs(args, true))
 			
 		case "complete":
 			if len(parts) < 2 {
@@ -206,8 +921,11 @@ func main() {
 				fmt.Println("Invalid task ID. Please enter a number.")
 				continue
 			}
-			if err := todoList.CompleteTask(id); err != nil {
+			newID, err := todoList.CompleteTask(id)
+			if err != nil {
 				fmt.Printf("Error: %v\n", err)
+			} else if newID != 0 {
+				fmt.Printf("Task %d marked as completed! Next occurrence created as task %d.\n", id, newID)
 			} else {
 				fmt.Printf("Task %d marked as completed!\n", id)
 			}
@@ -239,10 +957,69 @@ func main() {
 				fmt.Printf("Completion rate: %.1f%%\n", completionRate)
 			}
 			
+		case "import":
+			if len(parts) < 2 {
+				fmt.Println("Please provide a file path.")
+				continue
+			}
+			if err := todoList.LoadFromTodoTxt(parts[1]); err != nil {
+				fmt.Printf("Error importing tasks: %v\n", err)
+			} else {
+				fmt.Println("Tasks imported successfully!")
+			}
+
+		case "export":
+			if len(parts) < 2 {
+				fmt.Println("Please provide a file path.")
+				continue
+			}
+			if err := todoList.SaveToTodoTxt(parts[1]); err != nil {
+				fmt.Printf("Error exporting tasks: %v\n", err)
+			} else {
+				fmt.Println("Tasks exported successfully!")
+			}
+
+		case "edit":
+			if err := todoList.EditExternal(); err != nil {
+				fmt.Printf("Error editing tasks: %v\n", err)
+			} else {
+				fmt.Println("Tasks updated successfully!")
+			}
+
+		case "save":
+			force := len(parts) > 1 && strings.TrimSpace(parts[1]) == "--force"
+			if err := todoList.SaveToFile(force); err != nil {
+				if errors.Is(err, ErrListChangedOnDisk) {
+					fmt.Println("Error: the todo file changed on disk. Run 'reload' to pick up those changes, or 'save --force' to overwrite them.")
+				} else {
+					fmt.Printf("Error saving tasks: %v\n", err)
+				}
+			} else {
+				fmt.Println("Tasks saved successfully!")
+			}
+
+		case "reload":
+			if err := todoList.LoadFromFile(); err != nil {
+				fmt.Printf("Error reloading tasks: %v\n", err)
+			} else {
+				fmt.Println("Tasks reloaded from disk.")
+			}
+
+		case "status":
+			filter := ""
+			if len(parts) > 1 {
+				filter = parts[1]
+			}
+			fmt.Println(todoList.StatusLine(filter))
+
 		case "quit", "exit", "q":
 			fmt.Println("Saving tasks...")
-			if err := todoList.SaveToFile(); err != nil {
-				fmt.Printf("Error saving tasks: %v\n", err)
+			if err := todoList.SaveToFile(false); err != nil {
+				if errors.Is(err, ErrListChangedOnDisk) {
+					fmt.Println("Error: the todo file changed on disk. Run 'reload' then try again, or 'save --force' before quitting.")
+				} else {
+					fmt.Printf("Error saving tasks: %v\n", err)
+				}
 			} else {
 				fmt.Println("Tasks saved successfully!")
 			}
@@ -254,8 +1031,12 @@ func main() {
 		}
 		
 		// Auto-save after each operation (except quit)
-		if err := todoList.SaveToFile(); err != nil {
-			fmt.Printf("Warning: Could not save tasks: %v\n", err)
+		if err := todoList.SaveToFile(false); err != nil {
+			if errors.Is(err, ErrListChangedOnDisk) {
+				fmt.Println("Warning: todo file changed on disk since it was loaded; run 'reload' or 'save --force'.")
+			} else {
+				fmt.Printf("Warning: Could not save tasks: %v\n", err)
+			}
 		}
 	}
 }
\ No newline at end of file
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTaskRoundTrip(t *testing.T) {
+	lines := []string{
+		"2026-01-01 Buy milk +shopping",
+		"(A) 2026-01-01 Call mom +family @phone",
+		"2026-01-01 Plan trip +travel @planning due:2026-02-01",
+		"x 2026-01-05 2026-01-01 Finish report +work",
+	}
+
+	for _, line := range lines {
+		task, err := ParseTask(line)
+		if err != nil {
+			t.Fatalf("ParseTask(%q) returned error: %v", line, err)
+		}
+		if got := task.String(); got != line {
+			t.Errorf("round trip: ParseTask(%q).String() = %q, want %q", line, got, line)
+		}
+	}
+}
+
+func TestAddTaskPriorityDoesNotDuplicateOnExport(t *testing.T) {
+	tl := NewTodoList("")
+	tl.AddTask("(A) Call mom +family")
+
+	if tl.Tasks[0].Priority != "A" {
+		t.Fatalf("expected Priority to be parsed as A, got %q", tl.Tasks[0].Priority)
+	}
+	if strings.Contains(tl.Tasks[0].Description, "(A)") {
+		t.Fatalf("Description should not retain the priority marker, got %q", tl.Tasks[0].Description)
+	}
+
+	path := t.TempDir() + "/export.txt"
+	if err := tl.SaveToTodoTxt(path); err != nil {
+		t.Fatalf("SaveToTodoTxt: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	line := strings.TrimSpace(string(data))
+
+	if strings.Count(line, "(A)") != 1 {
+		t.Errorf("exported line has a duplicated priority marker: %q", line)
+	}
+}
+
+func TestSaveToFileDetectsConcurrentEdit(t *testing.T) {
+	path := t.TempDir() + "/todos.json"
+
+	tl := NewTodoList(path)
+	tl.AddTask("first")
+	if err := tl.SaveToFile(false); err != nil {
+		t.Fatalf("initial SaveToFile: %v", err)
+	}
+
+	// Simulate a second instance loading the same store and saving a change
+	// of its own.
+	other := NewTodoList(path)
+	if err := other.LoadFromFile(); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	other.AddTask("second")
+	if err := other.SaveToFile(false); err != nil {
+		t.Fatalf("concurrent SaveToFile: %v", err)
+	}
+
+	tl.AddTask("third")
+	if err := tl.SaveToFile(false); !errors.Is(err, ErrListChangedOnDisk) {
+		t.Fatalf("SaveToFile = %v, want ErrListChangedOnDisk", err)
+	}
+
+	if err := tl.SaveToFile(true); err != nil {
+		t.Fatalf("forced SaveToFile: %v", err)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	cases := []struct {
+		spec     string
+		amount   int
+		unit     byte
+		anchored bool
+	}{
+		{"1d", 1, 'd', false},
+		{"2w", 2, 'w', false},
+		{"3m", 3, 'm', false},
+		{"+1m", 1, 'm', true},
+	}
+
+	for _, c := range cases {
+		amount, unit, anchored, err := parseRecurrence(c.spec)
+		if err != nil {
+			t.Fatalf("parseRecurrence(%q) returned error: %v", c.spec, err)
+		}
+		if amount != c.amount || unit != c.unit || anchored != c.anchored {
+			t.Errorf("parseRecurrence(%q) = (%d, %q, %v), want (%d, %q, %v)",
+				c.spec, amount, string(unit), anchored, c.amount, string(c.unit), c.anchored)
+		}
+	}
+}
+
+func TestCompleteTaskRecurrenceDaily(t *testing.T) {
+	tl := NewTodoList("")
+	tl.AddTask("water plants rec:1d")
+	tl.Tasks[0].Tags = map[string]string{"rec": "1d"}
+
+	newID, err := tl.CompleteTask(1)
+	if err != nil {
+		t.Fatalf("CompleteTask returned error: %v", err)
+	}
+	if newID != 2 {
+		t.Fatalf("expected new task ID 2, got %d", newID)
+	}
+	if !tl.Tasks[0].Completed {
+		t.Fatal("original task was not marked completed")
+	}
+	if tl.Tasks[1].Completed {
+		t.Fatal("new recurrence instance should not start completed")
+	}
+}
+
+func TestCompleteTaskRecurrenceWeeklyAndMonthly(t *testing.T) {
+	due := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		rec    string
+		amount int
+		unit   string // "d", "w" (in days) or "m" (in months)
+	}{
+		{"2w", 14, "d"},
+		{"1m", 1, "m"},
+	}
+
+	for _, c := range cases {
+		now := time.Now()
+		tl := NewTodoList("")
+		tl.AddTask("task")
+		tl.Tasks[0].Tags = map[string]string{"rec": c.rec}
+		tl.Tasks[0].DueDate = &due
+
+		if _, err := tl.CompleteTask(1); err != nil {
+			t.Fatalf("CompleteTask returned error: %v", err)
+		}
+
+		// A floating (non-"+") rec: tag anchors to completion time, not the
+		// task's original due date, so the new due date should land near
+		// `now`, not near `due`.
+		var want time.Time
+		if c.unit == "m" {
+			want = now.AddDate(0, c.amount, 0)
+		} else {
+			want = now.AddDate(0, 0, c.amount)
+		}
+
+		got := tl.Tasks[1].DueDate
+		if got == nil {
+			t.Fatalf("rec:%s produced no due date", c.rec)
+		}
+		if diff := got.Sub(want); diff < -time.Minute || diff > time.Minute {
+			t.Errorf("rec:%s due date = %v, want ~%v (anchored to completion time, not %v)", c.rec, got, want, due)
+		}
+	}
+}
+
+func TestCompleteTaskRecurrenceAnchoring(t *testing.T) {
+	due := time.Now().AddDate(0, 0, -3) // 3 days overdue
+
+	floating := NewTodoList("")
+	floating.AddTask("floating")
+	floating.Tasks[0].Tags = map[string]string{"rec": "1d"}
+	floating.Tasks[0].DueDate = &due
+	if _, err := floating.CompleteTask(1); err != nil {
+		t.Fatalf("CompleteTask returned error: %v", err)
+	}
+	floatingNext := floating.Tasks[1].DueDate
+	if floatingNext == nil || floatingNext.Before(time.Now()) {
+		t.Fatalf("floating recurrence should be anchored to completion time, got %v", floatingNext)
+	}
+
+	anchored := NewTodoList("")
+	anchored.AddTask("anchored")
+	anchored.Tasks[0].Tags = map[string]string{"rec": "+1d"}
+	anchored.Tasks[0].DueDate = &due
+	if _, err := anchored.CompleteTask(1); err != nil {
+		t.Fatalf("CompleteTask returned error: %v", err)
+	}
+	anchoredNext := anchored.Tasks[1].DueDate
+	want := due.AddDate(0, 0, 1)
+	if anchoredNext == nil || !anchoredNext.Equal(want) {
+		t.Fatalf("anchored recurrence = %v, want %v", anchoredNext, want)
+	}
+}